@@ -0,0 +1,449 @@
+package gtf
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	textTemplate "text/template"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// normalizeForCompare reduces a reflect.Value down to a comparable Go value,
+// hexing primitive.ObjectID so Mongo ids compare cleanly against strings.
+func normalizeForCompare(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == reflect.TypeOf(primitive.ObjectID{}) {
+		return v.Interface().(primitive.ObjectID).Hex()
+	}
+
+	return v.Interface()
+}
+
+// fieldByPath resolves a dot-notated path ("Field.Sub.Path") against a
+// struct, map or pointer value using reflection, dereferencing pointers and
+// interfaces as it descends.
+func fieldByPath(value interface{}, path string) (reflect.Value, bool) {
+	v := reflect.ValueOf(value)
+	if path == "" {
+		return v, v.IsValid()
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(part)
+		case reflect.Map:
+			v = v.MapIndex(reflect.ValueOf(part))
+		default:
+			return reflect.Value{}, false
+		}
+
+		if !v.IsValid() {
+			return reflect.Value{}, false
+		}
+	}
+
+	return v, true
+}
+
+// compareOp evaluates a against b for the given operator. Both sides are
+// normalized first so primitive.ObjectID compares equal to its hex string.
+func compareOp(op string, a, b interface{}) (bool, error) {
+	a = normalizeForCompare(reflect.ValueOf(a))
+	b = normalizeForCompare(reflect.ValueOf(b))
+
+	switch op {
+	case "eq", "":
+		return fmt.Sprint(a) == fmt.Sprint(b), nil
+	case "ne":
+		return fmt.Sprint(a) != fmt.Sprint(b), nil
+	case "in", "not in":
+		bv := reflect.ValueOf(b)
+		if bv.Kind() != reflect.Slice && bv.Kind() != reflect.Array {
+			return false, fmt.Errorf("gtf: where: %q requires a slice value, got %T", op, b)
+		}
+		found := false
+		for i := 0; i < bv.Len(); i++ {
+			if fmt.Sprint(normalizeForCompare(bv.Index(i))) == fmt.Sprint(a) {
+				found = true
+				break
+			}
+		}
+		if op == "in" {
+			return found, nil
+		}
+		return !found, nil
+	case "intersect":
+		av := reflect.ValueOf(a)
+		bv := reflect.ValueOf(b)
+		if av.Kind() != reflect.Slice && av.Kind() != reflect.Array {
+			return false, fmt.Errorf("gtf: where: intersect requires a slice field, got %T", a)
+		}
+		if bv.Kind() != reflect.Slice && bv.Kind() != reflect.Array {
+			return false, fmt.Errorf("gtf: where: intersect requires a slice value, got %T", b)
+		}
+		for i := 0; i < av.Len(); i++ {
+			for j := 0; j < bv.Len(); j++ {
+				if fmt.Sprint(normalizeForCompare(av.Index(i))) == fmt.Sprint(normalizeForCompare(bv.Index(j))) {
+					return true, nil
+				}
+			}
+		}
+		return false, nil
+	}
+
+	af, aok := toFloat(a)
+	bf, bok := toFloat(b)
+	if !aok || !bok {
+		return false, fmt.Errorf("gtf: where: operator %q requires numeric operands, got %T and %T", op, a, b)
+	}
+
+	switch op {
+	case "lt":
+		return af < bf, nil
+	case "le":
+		return af <= bf, nil
+	case "gt":
+		return af > bf, nil
+	case "ge":
+		return af >= bf, nil
+	}
+
+	return false, fmt.Errorf("gtf: where: unknown operator %q", op)
+}
+
+// toFloat converts numeric reflect kinds (and time.Time via Unix seconds) to
+// float64 so operators like lt/gt can compare heterogeneous numeric types.
+func toFloat(value interface{}) (float64, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+
+	if t, ok := value.(interface{ Unix() int64 }); ok {
+		return float64(t.Unix()), true
+	}
+
+	return 0, false
+}
+
+// elements returns value as a []interface{}, dereferencing a pointer to a
+// slice/array first. It errors for anything that isn't list-shaped.
+func elements(value interface{}) ([]interface{}, error) {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil, fmt.Errorf("gtf: expected a slice or array, got %T", value)
+	}
+
+	out := make([]interface{}, v.Len())
+	for i := range out {
+		out[i] = v.Index(i).Interface()
+	}
+	return out, nil
+}
+
+// uniqKey returns the string form used to dedup/compare elements in uniq,
+// intersect, union and symdiff.
+func uniqKey(value interface{}) string {
+	return fmt.Sprint(normalizeForCompare(reflect.ValueOf(value)))
+}
+
+var GtfCollectionFuncMap = textTemplate.FuncMap{
+	// where filters list for elements whose Field.Path compares to value
+	// under op (default "eq"): where list "Field.Path" ["op"] value
+	"where": func(list interface{}, path string, args ...interface{}) ([]interface{}, error) {
+		if len(args) == 0 || len(args) > 2 {
+			return nil, fmt.Errorf("gtf: where: expected (list, path, [op,] value), got %d extra args", len(args))
+		}
+
+		op := "eq"
+		value := args[0]
+		if len(args) == 2 {
+			op = fmt.Sprint(args[0])
+			value = args[1]
+		}
+
+		items, err := elements(list)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: where: %w", err)
+		}
+
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			fv, ok := fieldByPath(item, path)
+			if !ok {
+				continue
+			}
+
+			matched, err := compareOp(op, normalizeForCompare(fv), value)
+			if err != nil {
+				return nil, fmt.Errorf("gtf: where: %w", err)
+			}
+			if matched {
+				result = append(result, item)
+			}
+		}
+
+		return result, nil
+	},
+
+	// sort returns a new slice ordered by an optional Field.Path, ascending
+	// unless the last arg is "desc": sort list ["Field.Path"] ["asc"|"desc"]
+	"sort": func(list interface{}, args ...string) ([]interface{}, error) {
+		items, err := elements(list)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: sort: %w", err)
+		}
+
+		path := ""
+		desc := false
+		for _, arg := range args {
+			switch arg {
+			case "asc":
+				desc = false
+			case "desc":
+				desc = true
+			default:
+				path = arg
+			}
+		}
+
+		keyOf := func(item interface{}) interface{} {
+			if path == "" {
+				return item
+			}
+			fv, ok := fieldByPath(item, path)
+			if !ok {
+				return nil
+			}
+			return normalizeForCompare(fv)
+		}
+
+		sorted := make([]interface{}, len(items))
+		copy(sorted, items)
+
+		sort.SliceStable(sorted, func(i, j int) bool {
+			less := lessValue(keyOf(sorted[i]), keyOf(sorted[j]))
+			if desc {
+				return !less && fmt.Sprint(keyOf(sorted[i])) != fmt.Sprint(keyOf(sorted[j]))
+			}
+			return less
+		})
+
+		return sorted, nil
+	},
+
+	// groupBy buckets list into map[string][]interface{} keyed by Field.Path.
+	"groupBy": func(list interface{}, path string) (map[string][]interface{}, error) {
+		items, err := elements(list)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: groupBy: %w", err)
+		}
+
+		groups := map[string][]interface{}{}
+		for _, item := range items {
+			fv, ok := fieldByPath(item, path)
+			if !ok {
+				continue
+			}
+			key := fmt.Sprint(normalizeForCompare(fv))
+			groups[key] = append(groups[key], item)
+		}
+
+		return groups, nil
+	},
+
+	// uniq dedups list, comparing elements by their normalized string form.
+	"uniq": func(list interface{}) ([]interface{}, error) {
+		items, err := elements(list)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: uniq: %w", err)
+		}
+
+		seen := map[string]bool{}
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			key := uniqKey(item)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			result = append(result, item)
+		}
+
+		return result, nil
+	},
+
+	// intersect returns the elements present in both a and b.
+	"intersect": func(a, b interface{}) ([]interface{}, error) {
+		return setOp(a, b, func(inA, inB bool) bool { return inA && inB })
+	},
+
+	// union returns the deduped elements present in either a or b.
+	"union": func(a, b interface{}) ([]interface{}, error) {
+		return setOp(a, b, func(inA, inB bool) bool { return inA || inB })
+	},
+
+	// symdiff returns the elements present in exactly one of a or b.
+	"symdiff": func(a, b interface{}) ([]interface{}, error) {
+		return setOp(a, b, func(inA, inB bool) bool { return inA != inB })
+	},
+
+	// apply calls a previously registered gtf function on every element of
+	// list: apply list "funcName" args... and collects its results. It
+	// resolves funcName against GtfTextFuncMap, the legacy (panic-recovering)
+	// set; see strict.go for the GtfStrictFuncMap-aware variant used by
+	// NewStrict/InjectStrict.
+	"apply": applyWith(func(name string) (interface{}, bool) {
+		fn, ok := GtfTextFuncMap[name]
+		return fn, ok
+	}),
+}
+
+// applyWith builds an "apply" implementation that resolves funcName via
+// lookup, so the legacy and strict function sets can each dispatch to their
+// own registered functions instead of always going through one global map.
+func applyWith(lookup func(name string) (interface{}, bool)) func(interface{}, string, ...interface{}) ([]interface{}, error) {
+	return func(list interface{}, funcName string, args ...interface{}) ([]interface{}, error) {
+		fn, ok := lookup(funcName)
+		if !ok {
+			return nil, fmt.Errorf("gtf: apply: unknown function %q", funcName)
+		}
+
+		fv := reflect.ValueOf(fn)
+		if fv.Kind() != reflect.Func {
+			return nil, fmt.Errorf("gtf: apply: %q is not callable", funcName)
+		}
+
+		items, err := elements(list)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: apply: %w", err)
+		}
+
+		result := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			// Matches gtf's own calling convention: the piped/target value
+			// is the last argument (divisibleby(arg, value), join(arg,
+			// value), slice(start, end, value), ...).
+			callArgs := make([]reflect.Value, 0, len(args)+1)
+			for _, a := range args {
+				callArgs = append(callArgs, reflect.ValueOf(a))
+			}
+			callArgs = append(callArgs, reflect.ValueOf(item))
+
+			out := fv.Call(callArgs)
+			if len(out) == 0 {
+				result = append(result, nil)
+				continue
+			}
+
+			last := out[len(out)-1]
+			if last.Type() == errorType {
+				if !last.IsNil() {
+					return nil, fmt.Errorf("gtf: apply: %s: %v", funcName, last.Interface())
+				}
+				result = append(result, out[0].Interface())
+				continue
+			}
+
+			result = append(result, out[len(out)-1].Interface())
+		}
+
+		return result, nil
+	}
+}
+
+// lessValue orders two normalized values, falling back to string comparison
+// for kinds that don't support native ordering.
+func lessValue(a, b interface{}) bool {
+	if af, aok := toFloat(a); aok {
+		if bf, bok := toFloat(b); bok {
+			return af < bf
+		}
+	}
+	return fmt.Sprint(a) < fmt.Sprint(b)
+}
+
+// setOp implements intersect/union/symdiff by membership-testing the deduped
+// union of a and b against a keep predicate.
+func setOp(a, b interface{}, keep func(inA, inB bool) bool) ([]interface{}, error) {
+	aItems, err := elements(a)
+	if err != nil {
+		return nil, fmt.Errorf("gtf: %w", err)
+	}
+	bItems, err := elements(b)
+	if err != nil {
+		return nil, fmt.Errorf("gtf: %w", err)
+	}
+
+	aSet := map[string]interface{}{}
+	for _, item := range aItems {
+		aSet[uniqKey(item)] = item
+	}
+	bSet := map[string]interface{}{}
+	for _, item := range bItems {
+		bSet[uniqKey(item)] = item
+	}
+
+	seen := map[string]bool{}
+	result := make([]interface{}, 0, len(aItems)+len(bItems))
+
+	appendIfKept := func(key string, item interface{}) {
+		if seen[key] {
+			return
+		}
+		_, inA := aSet[key]
+		_, inB := bSet[key]
+		if keep(inA, inB) {
+			result = append(result, item)
+		}
+		seen[key] = true
+	}
+
+	for _, item := range aItems {
+		appendIfKept(uniqKey(item), item)
+	}
+	for _, item := range bItems {
+		appendIfKept(uniqKey(item), item)
+	}
+
+	return result, nil
+}
+
+func init() {
+	for k, v := range GtfCollectionFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
@@ -0,0 +1,265 @@
+package gtf
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type collectionTestPerson struct {
+	Name string
+	Age  int
+	ID   primitive.ObjectID
+}
+
+func TestWhere(t *testing.T) {
+	aliceID := primitive.NewObjectID()
+	bobID := primitive.NewObjectID()
+
+	people := []collectionTestPerson{
+		{Name: "Alice", Age: 30, ID: aliceID},
+		{Name: "Bob", Age: 25, ID: bobID},
+		{Name: "Carol", Age: 30, ID: primitive.NewObjectID()},
+	}
+	pointers := []*collectionTestPerson{&people[0], &people[1], &people[2]}
+	maps := []map[string]interface{}{
+		{"Name": "Alice", "Age": 30},
+		{"Name": "Bob", "Age": 25},
+	}
+
+	whereFn := GtfCollectionFuncMap["where"].(func(interface{}, string, ...interface{}) ([]interface{}, error))
+
+	cases := []struct {
+		name string
+		list interface{}
+		path string
+		args []interface{}
+		want int
+	}{
+		{"struct default eq", people, "Name", []interface{}{"Alice"}, 1},
+		{"struct explicit eq", people, "Age", []interface{}{"eq", 30}, 2},
+		{"struct ne", people, "Age", []interface{}{"ne", 30}, 1},
+		{"struct lt", people, "Age", []interface{}{"lt", 30}, 1},
+		{"struct ge", people, "Age", []interface{}{"ge", 30}, 2},
+		{"pointer slice", pointers, "Name", []interface{}{"Bob"}, 1},
+		{"map slice", maps, "Name", []interface{}{"Alice"}, 1},
+		{"objectid hex compare", people, "ID", []interface{}{aliceID.Hex()}, 1},
+		{"in op", people, "Name", []interface{}{"in", []string{"Alice", "Bob"}}, 2},
+		{"not in op", people, "Name", []interface{}{"not in", []string{"Alice", "Bob"}}, 1},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := whereFn(c.list, c.path, c.args...)
+			if err != nil {
+				t.Fatalf("where: unexpected error: %v", err)
+			}
+			if len(got) != c.want {
+				t.Errorf("where(%v) = %d results, want %d", c.args, len(got), c.want)
+			}
+		})
+	}
+}
+
+func TestWhereIntersectOp(t *testing.T) {
+	type tagged struct {
+		Tags []string
+	}
+	list := []tagged{
+		{Tags: []string{"a", "b"}},
+		{Tags: []string{"c"}},
+	}
+
+	whereFn := GtfCollectionFuncMap["where"].(func(interface{}, string, ...interface{}) ([]interface{}, error))
+
+	got, err := whereFn(list, "Tags", "intersect", []string{"b", "z"})
+	if err != nil {
+		t.Fatalf("where intersect: unexpected error: %v", err)
+	}
+	if len(got) != 1 {
+		t.Fatalf("where intersect: got %d results, want 1", len(got))
+	}
+}
+
+func TestSort(t *testing.T) {
+	people := []collectionTestPerson{
+		{Name: "Carol", Age: 30},
+		{Name: "Alice", Age: 30},
+		{Name: "Bob", Age: 25},
+	}
+
+	sortFn := GtfCollectionFuncMap["sort"].(func(interface{}, ...string) ([]interface{}, error))
+
+	t.Run("by field asc", func(t *testing.T) {
+		got, err := sortFn(people, "Age")
+		if err != nil {
+			t.Fatalf("sort: unexpected error: %v", err)
+		}
+		ages := make([]int, len(got))
+		for i, v := range got {
+			ages[i] = v.(collectionTestPerson).Age
+		}
+		if !sort.IntsAreSorted(ages) {
+			t.Errorf("sort: ages not ascending: %v", ages)
+		}
+	})
+
+	t.Run("by field desc", func(t *testing.T) {
+		got, err := sortFn(people, "Age", "desc")
+		if err != nil {
+			t.Fatalf("sort: unexpected error: %v", err)
+		}
+		first := got[0].(collectionTestPerson)
+		last := got[len(got)-1].(collectionTestPerson)
+		if first.Age < last.Age {
+			t.Errorf("sort desc: got ascending order")
+		}
+	})
+
+	t.Run("stable on ties", func(t *testing.T) {
+		got, err := sortFn(people, "Age")
+		if err != nil {
+			t.Fatalf("sort: unexpected error: %v", err)
+		}
+		// Carol and Alice both have Age 30 and appear in that order in the
+		// input; a stable sort keeps Carol before Alice.
+		if got[1].(collectionTestPerson).Name != "Carol" || got[2].(collectionTestPerson).Name != "Alice" {
+			t.Errorf("sort: ties not stable, got %v", got)
+		}
+	})
+
+	t.Run("pointer slice", func(t *testing.T) {
+		ptrs := []*collectionTestPerson{&people[0], &people[1], &people[2]}
+		got, err := sortFn(ptrs, "Age")
+		if err != nil {
+			t.Fatalf("sort: unexpected error: %v", err)
+		}
+		if got[0].(*collectionTestPerson).Age != 25 {
+			t.Errorf("sort pointers: got %v", got)
+		}
+	})
+}
+
+func TestGroupBy(t *testing.T) {
+	people := []collectionTestPerson{
+		{Name: "Alice", Age: 30},
+		{Name: "Bob", Age: 25},
+		{Name: "Carol", Age: 30},
+	}
+
+	groupByFn := GtfCollectionFuncMap["groupBy"].(func(interface{}, string) (map[string][]interface{}, error))
+
+	got, err := groupByFn(people, "Age")
+	if err != nil {
+		t.Fatalf("groupBy: unexpected error: %v", err)
+	}
+	if len(got["30"]) != 2 || len(got["25"]) != 1 {
+		t.Errorf("groupBy: got %v", got)
+	}
+
+	t.Run("objectid keys", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		maps := []map[string]interface{}{
+			{"ID": id, "Name": "a"},
+			{"ID": id, "Name": "b"},
+		}
+		got, err := groupByFn(maps, "ID")
+		if err != nil {
+			t.Fatalf("groupBy objectid: unexpected error: %v", err)
+		}
+		if len(got[id.Hex()]) != 2 {
+			t.Errorf("groupBy objectid: got %v, want key %s with 2 entries", got, id.Hex())
+		}
+	})
+}
+
+func TestUniq(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	cases := []struct {
+		name string
+		list interface{}
+		want int
+	}{
+		{"ints", []int{1, 2, 2, 3, 1}, 3},
+		{"strings", []string{"a", "b", "a"}, 2},
+		{"objectids", []primitive.ObjectID{id, id, primitive.NewObjectID()}, 2},
+	}
+
+	uniqFn := GtfCollectionFuncMap["uniq"].(func(interface{}) ([]interface{}, error))
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := uniqFn(c.list)
+			if err != nil {
+				t.Fatalf("uniq: unexpected error: %v", err)
+			}
+			if len(got) != c.want {
+				t.Errorf("uniq(%v) = %d results, want %d", c.list, len(got), c.want)
+			}
+		})
+	}
+}
+
+func TestSetOps(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := []int{2, 3, 4}
+
+	intersectFn := GtfCollectionFuncMap["intersect"].(func(interface{}, interface{}) ([]interface{}, error))
+	unionFn := GtfCollectionFuncMap["union"].(func(interface{}, interface{}) ([]interface{}, error))
+	symdiffFn := GtfCollectionFuncMap["symdiff"].(func(interface{}, interface{}) ([]interface{}, error))
+
+	if got, err := intersectFn(a, b); err != nil || len(got) != 2 {
+		t.Errorf("intersect(%v, %v) = %v, err %v; want 2 elements", a, b, got, err)
+	}
+	if got, err := unionFn(a, b); err != nil || len(got) != 4 {
+		t.Errorf("union(%v, %v) = %v, err %v; want 4 elements", a, b, got, err)
+	}
+	if got, err := symdiffFn(a, b); err != nil || len(got) != 2 {
+		t.Errorf("symdiff(%v, %v) = %v, err %v; want 2 elements", a, b, got, err)
+	}
+}
+
+func TestApply(t *testing.T) {
+	applyFn := GtfCollectionFuncMap["apply"].(func(interface{}, string, ...interface{}) ([]interface{}, error))
+
+	got, err := applyFn([]string{"a", "b"}, "upper")
+	if err != nil {
+		t.Fatalf("apply: unexpected error: %v", err)
+	}
+	want := []interface{}{"A", "B"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("apply(upper) = %v, want %v", got, want)
+	}
+
+	if _, err := applyFn([]string{"a"}, "noSuchFunc"); err == nil {
+		t.Errorf("apply: expected error for unknown function")
+	}
+
+	t.Run("multi-arg puts piped value last", func(t *testing.T) {
+		divisiblebyFn := GtfTextFuncMap["divisibleby"].(func(interface{}, interface{}) bool)
+		if divisiblebyFn(3, 6) != true {
+			t.Fatalf("sanity check: divisibleby(3, 6) should be true")
+		}
+
+		got, err := applyFn([]interface{}{6, 9, 10}, "divisibleby", 3)
+		if err != nil {
+			t.Fatalf("apply(divisibleby): unexpected error: %v", err)
+		}
+		want := []interface{}{true, true, false}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("apply(divisibleby, 3) = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestCompareOpInNotInRequiresSlice(t *testing.T) {
+	if _, err := compareOp("in", "a", "not a slice"); err == nil {
+		t.Errorf("compareOp(in): expected error when b is not a slice/array")
+	}
+	if _, err := compareOp("not in", "a", "not a slice"); err == nil {
+		t.Errorf("compareOp(not in): expected error when b is not a slice/array")
+	}
+}
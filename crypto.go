@@ -0,0 +1,83 @@
+package gtf
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+
+	textTemplate "text/template"
+)
+
+// GtfCryptoFuncMap holds the cryptographic helpers (hashing, hmac, base64
+// and hex encoding) for templates that build signed URLs, webhook
+// signatures or cache keys. It is merged into GtfFuncMap but can also be
+// imported on its own by callers who only want this group.
+var GtfCryptoFuncMap = textTemplate.FuncMap{
+	"md5": func(s string) string {
+		sum := md5.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	"sha1": func(s string) string {
+		sum := sha1.Sum([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	"sha256": func(s string) string {
+		sum := sha256.Sum256([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	"sha512": func(s string) string {
+		sum := sha512.Sum512([]byte(s))
+		return hex.EncodeToString(sum[:])
+	},
+	"hmacSHA256": func(key, msg string) string {
+		mac := hmac.New(sha256.New, []byte(key))
+		mac.Write([]byte(msg))
+		return hex.EncodeToString(mac.Sum(nil))
+	},
+	"hmacSHA1": func(key, msg string) string {
+		mac := hmac.New(sha1.New, []byte(key))
+		mac.Write([]byte(msg))
+		return hex.EncodeToString(mac.Sum(nil))
+	},
+	"base64Encode": func(s string) string {
+		return base64.StdEncoding.EncodeToString([]byte(s))
+	},
+	"base64Decode": func(s string) (string, error) {
+		out, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return "", fmt.Errorf("gtf: base64Decode: %w", err)
+		}
+		return string(out), nil
+	},
+	"base64URLEncode": func(s string) string {
+		return base64.URLEncoding.EncodeToString([]byte(s))
+	},
+	"base64URLDecode": func(s string) (string, error) {
+		out, err := base64.URLEncoding.DecodeString(s)
+		if err != nil {
+			return "", fmt.Errorf("gtf: base64URLDecode: %w", err)
+		}
+		return string(out), nil
+	},
+	"hexEncode": func(s string) string {
+		return hex.EncodeToString([]byte(s))
+	},
+	"hexDecode": func(s string) (string, error) {
+		out, err := hex.DecodeString(s)
+		if err != nil {
+			return "", fmt.Errorf("gtf: hexDecode: %w", err)
+		}
+		return string(out), nil
+	},
+}
+
+func init() {
+	for k, v := range GtfCryptoFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
@@ -0,0 +1,134 @@
+package gtf
+
+import (
+	"fmt"
+	"sort"
+
+	textTemplate "text/template"
+)
+
+// mergeInto deep-merges src into dst, recursing into nested
+// map[string]interface{} values so callers can layer partial overrides.
+// Nested maps are always copied rather than aliased, so mutating the
+// result (or a later merge into it) never reaches back into src.
+func mergeInto(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sv, ok := v.(map[string]interface{}); ok {
+			dv, ok := dst[k].(map[string]interface{})
+			if !ok {
+				dv = map[string]interface{}{}
+			}
+			mergeInto(dv, sv)
+			dst[k] = dv
+			continue
+		}
+		dst[k] = v
+	}
+}
+
+var GtfDictFuncMap = textTemplate.FuncMap{
+	// dict builds a map[string]any from alternating key/value args:
+	// dict "k1" v1 "k2" v2 ...
+	"dict": func(values ...interface{}) (map[string]interface{}, error) {
+		if len(values)%2 != 0 {
+			return nil, fmt.Errorf("gtf: dict: expected an even number of arguments, got %d", len(values))
+		}
+
+		d := make(map[string]interface{}, len(values)/2)
+		for i := 0; i < len(values); i += 2 {
+			key, ok := values[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("gtf: dict: key %d must be a string, got %T", i/2, values[i])
+			}
+			d[key] = values[i+1]
+		}
+		return d, nil
+	},
+
+	// merge deep-merges d2, d3, ... into a copy of d1, left-to-right.
+	"merge": func(dicts ...map[string]interface{}) map[string]interface{} {
+		result := map[string]interface{}{}
+		for _, d := range dicts {
+			mergeInto(result, d)
+		}
+		return result
+	},
+
+	"hasKey": func(d map[string]interface{}, key string) bool {
+		_, ok := d[key]
+		return ok
+	},
+
+	// get reads key from d, traversing dotted paths through nested
+	// maps/structs via reflection.
+	"get": func(d map[string]interface{}, key string) interface{} {
+		v, ok := fieldByPath(d, key)
+		if !ok {
+			return nil
+		}
+		return v.Interface()
+	},
+
+	// set returns a copy of d with key set to value.
+	"set": func(d map[string]interface{}, key string, value interface{}) map[string]interface{} {
+		out := make(map[string]interface{}, len(d)+1)
+		for k, v := range d {
+			out[k] = v
+		}
+		out[key] = value
+		return out
+	},
+
+	// unset returns a copy of d with key removed.
+	"unset": func(d map[string]interface{}, key string) map[string]interface{} {
+		out := make(map[string]interface{}, len(d))
+		for k, v := range d {
+			if k == key {
+				continue
+			}
+			out[k] = v
+		}
+		return out
+	},
+
+	"keys": func(d map[string]interface{}) []string {
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		return keys
+	},
+
+	"values": func(d map[string]interface{}) []interface{} {
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			values[i] = d[k]
+		}
+		return values
+	},
+
+	// pluck collects the value at key from each of the given maps, skipping
+	// maps that don't have it: pluck "k" maps...
+	"pluck": func(key string, dicts ...map[string]interface{}) []interface{} {
+		result := make([]interface{}, 0, len(dicts))
+		for _, d := range dicts {
+			if v, ok := d[key]; ok {
+				result = append(result, v)
+			}
+		}
+		return result
+	},
+}
+
+func init() {
+	for k, v := range GtfDictFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
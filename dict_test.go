@@ -0,0 +1,54 @@
+package gtf
+
+import "testing"
+
+func TestMergeDoesNotAliasNestedMaps(t *testing.T) {
+	mergeFn := GtfDictFuncMap["merge"].(func(...map[string]interface{}) map[string]interface{})
+
+	base := map[string]interface{}{
+		"settings": map[string]interface{}{"theme": "light"},
+	}
+	override := map[string]interface{}{
+		"name": "override-only-key",
+	}
+
+	got := mergeFn(base, override)
+
+	nested, ok := got["settings"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("merge: settings = %v, want a map", got["settings"])
+	}
+	nested["theme"] = "dark"
+
+	if base["settings"].(map[string]interface{})["theme"] != "light" {
+		t.Errorf("merge: mutating the result's nested map also mutated the source, want independent copies")
+	}
+}
+
+func TestMergeDeepMerge(t *testing.T) {
+	mergeFn := GtfDictFuncMap["merge"].(func(...map[string]interface{}) map[string]interface{})
+
+	d1 := map[string]interface{}{
+		"a": 1,
+		"nested": map[string]interface{}{
+			"x": 1,
+			"y": 2,
+		},
+	}
+	d2 := map[string]interface{}{
+		"b": 2,
+		"nested": map[string]interface{}{
+			"y": 3,
+		},
+	}
+
+	got := mergeFn(d1, d2)
+
+	if got["a"] != 1 || got["b"] != 2 {
+		t.Errorf("merge: top-level keys = %v, want a=1 b=2", got)
+	}
+	nested := got["nested"].(map[string]interface{})
+	if nested["x"] != 1 || nested["y"] != 3 {
+		t.Errorf("merge: nested = %v, want x=1 y=3", nested)
+	}
+}
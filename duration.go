@@ -0,0 +1,143 @@
+package gtf
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	textTemplate "text/template"
+)
+
+// toDuration coerces a time.Duration, an integer/float second count, or a
+// time.Time into a time.Duration, so the helpers below can be used either
+// with a duration already in hand or with a raw timestamp.
+func toDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case time.Duration:
+		return v, nil
+	case time.Time:
+		return time.Since(v), nil
+	case int:
+		return time.Duration(v) * time.Second, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	}
+	return 0, fmt.Errorf("gtf: expected a time.Duration, time.Time or numeric seconds, got %T", value)
+}
+
+// durationUnits maps a granularity name to its size, in order from largest
+// to smallest, for both durationHuman's component breakdown and
+// durationRound's rounding.
+var durationUnits = []struct {
+	name string
+	size time.Duration
+}{
+	{"d", 24 * time.Hour},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+var GtfDurationFuncMap = textTemplate.FuncMap{
+	// durationHuman formats value as "2d 3h 5m", dropping zero components
+	// and stopping after the given number of components (default 2).
+	"durationHuman": func(value interface{}, components ...int) (string, error) {
+		d, err := toDuration(value)
+		if err != nil {
+			return "", fmt.Errorf("gtf: durationHuman: %w", err)
+		}
+
+		max := 2
+		if len(components) > 0 {
+			max = components[0]
+		}
+
+		neg := d < 0
+		if neg {
+			d = -d
+		}
+
+		parts := []string{}
+		for _, u := range durationUnits {
+			if len(parts) >= max {
+				break
+			}
+			if d < u.size {
+				continue
+			}
+			n := d / u.size
+			d -= n * u.size
+			parts = append(parts, fmt.Sprintf("%d%s", n, u.name))
+		}
+
+		if len(parts) == 0 {
+			return "0s", nil
+		}
+
+		out := strings.Join(parts, " ")
+		if neg {
+			out = "-" + out
+		}
+		return out, nil
+	},
+
+	// durationRound rounds value to the nearest whole unit ("s", "m", "h"
+	// or "d") and returns the rounded time.Duration.
+	"durationRound": func(value interface{}, unit string) (time.Duration, error) {
+		d, err := toDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: durationRound: %w", err)
+		}
+
+		for _, u := range durationUnits {
+			if u.name == unit {
+				return d.Round(u.size), nil
+			}
+		}
+
+		return 0, fmt.Errorf("gtf: durationRound: unknown unit %q", unit)
+	},
+
+	// since returns how long ago t was.
+	"since": func(t time.Time) time.Duration {
+		return time.Since(t)
+	},
+
+	// until returns how long it is until t.
+	"until": func(t time.Time) time.Duration {
+		return time.Until(t)
+	},
+
+	// ageColor returns a CSS class name describing how stale t is, for
+	// dashboards showing branch/record age: "ok" under warnAfter, "warn"
+	// under critAfter, otherwise "crit".
+	"ageColor": func(t time.Time, warnAfter, critAfter interface{}) (string, error) {
+		age := time.Since(t)
+
+		warn, err := toDuration(warnAfter)
+		if err != nil {
+			return "", fmt.Errorf("gtf: ageColor: %w", err)
+		}
+		crit, err := toDuration(critAfter)
+		if err != nil {
+			return "", fmt.Errorf("gtf: ageColor: %w", err)
+		}
+
+		switch {
+		case age < warn:
+			return "ok", nil
+		case age < crit:
+			return "warn", nil
+		default:
+			return "crit", nil
+		}
+	},
+}
+
+func init() {
+	for k, v := range GtfDurationFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
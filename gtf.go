@@ -26,14 +26,14 @@ import (
 
 var striptagsRegexp = regexp.MustCompile("<[^>]*?>")
 
-// recovery will silently swallow all unexpected panics.
-func recovery() {
-	recover()
-}
-
-var GtfTextFuncMap = textTemplate.FuncMap{
+// gtfRawFuncMap holds the functions' raw logic, which panics on invalid
+// input (an empty slice to first, a non-*bool to istrue, a zero divisor to
+// divisibleby, ...) instead of guarding against it. GtfTextFuncMap and
+// GtfStrictTextFuncMap both wrap these, differing only in what happens to
+// that panic: the former recovers it to the zero value as gtf has always
+// done, the latter turns it into a real error. See strict.go.
+var gtfRawFuncMap = textTemplate.FuncMap{
 	"toValue": func(value interface{}) interface{} {
-		defer recover()
 		// convert primitive.ObjectID to string
 		switch value.(type) {
 		case primitive.ObjectID:
@@ -43,7 +43,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		}
 	},
 	"timeIn": func(t time.Time, locName string) string {
-		defer recovery()
 		if t.IsZero() {
 			return ""
 		}
@@ -59,21 +58,17 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return t.Format("2006-01-02 15:04 -07")
 	},
 	"funcMap": func(v ...interface{}) []interface{} {
-		defer recovery()
 		return v
 	},
 
 	"asQuery": func(query string) string {
-		defer recovery()
 		return url.QueryEscape(query)
 	},
 
 	"asURL": func(query string) template.URL {
-		defer recovery()
 		return template.URL(query)
 	},
 	"isChecked": func(values interface{}, option interface{}) string {
-		defer recovery()
 		list := []string{}
 		switch values.(type) {
 		case string:
@@ -90,7 +85,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return ""
 	},
 	"objectId": func(value interface{}) string {
-		defer recovery()
 		switch value.(type) {
 		case primitive.ObjectID:
 			return value.(primitive.ObjectID).Hex()
@@ -99,18 +93,15 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		}
 	},
 	"parseUrl": func(path string, r *http.Request) string {
-		defer recovery()
 		link, _ := url.ParseRequestURI(r.RequestURI)
 		link.Path = path
 		return link.String()
 	},
 	"duration": func(start, stop time.Time) float64 {
-		defer recovery()
 		loading := stop.Sub(start).Seconds()
 		return loading
 	},
 	"existobjectid": func(values []primitive.ObjectID, id string) bool {
-		defer recovery()
 		for _, value := range values {
 			if value.Hex() == id {
 				return true
@@ -119,7 +110,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return false
 	},
 	"sameobjectid": func(value interface{}, id string) bool {
-		defer recovery()
 		switch value.(type) {
 		case primitive.ObjectID:
 			return value.(primitive.ObjectID).Hex() == id
@@ -128,7 +118,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		}
 	},
 	"minus": func(value interface{}, i int) int {
-		defer recovery()
 		v := reflect.ValueOf(value)
 		switch v.Kind() {
 		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
@@ -139,7 +128,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 	},
 
 	"delQuery": func(r *http.Request, k string) string {
-		defer recovery()
 		link, _ := url.ParseRequestURI(r.RequestURI)
 		values := link.Query()
 
@@ -148,7 +136,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return link.String()
 	},
 	"setQuery": func(r *http.Request, k, v string) string {
-		defer recovery()
 		link, _ := url.ParseRequestURI(r.RequestURI)
 		values := link.Query()
 
@@ -157,17 +144,14 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return link.String()
 	},
 	"getQuery": func(r *http.Request, k string) string {
-		defer recovery()
 		link, _ := url.ParseRequestURI(r.RequestURI)
 		values := link.Query()
 		return values.Get(k)
 	},
 	"repeat": func(count int, str string) string {
-		defer recovery()
 		return strings.Repeat(str, count)
 	},
 	"getInt": func(value interface{}) int {
-		defer recovery()
 		if value == nil {
 			return 0
 		}
@@ -179,7 +163,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		}
 	},
 	"istrue": func(value interface{}) bool {
-		defer recovery()
 		//for *bool type
 		v := value.(*bool)
 		if v == nil || *v == false {
@@ -188,7 +171,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return true
 	},
 	"humanizeSize": func(size interface{}) string {
-		defer recovery()
 		switch v := size.(type) {
 		case float64:
 			out := uint64(int64(v))
@@ -199,7 +181,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return "NA"
 	},
 	"isblank": func(value string) bool {
-		defer recovery()
 		s := strings.TrimSpace(value)
 		if s == "" {
 			return true
@@ -207,7 +188,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return false
 	},
 	"renderTime": func(value interface{}) string {
-		defer recovery()
 		switch value.(type) {
 		case time.Time:
 			startTime := value.(time.Time)
@@ -226,35 +206,27 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return template.HTML(output)
 	},
 	"markdown": func(value string) template.HTML {
-		defer recovery()
-
 		md := []byte(value)
 		output := blackfriday.Run(md)
 
 		return template.HTML(string(output))
 	},
 	"timeago": func(value time.Time) string {
-		defer recovery()
 		return timeago.English.Format(value)
 	},
 	"asHTMLAttr": func(value string) template.HTMLAttr {
-		defer recovery()
 		return template.HTMLAttr(value)
 	},
 	"asCSS": func(value string) template.CSS {
-		defer recovery()
 		return template.CSS(value)
 	},
 	"asHTML": func(value string) template.HTML {
-		defer recovery()
 		return template.HTML(value)
 	},
 	"asJS": func(value string) template.JS {
-		defer recovery()
 		return template.JS(value)
 	},
 	"existin": func(list interface{}, value string) bool {
-		defer recovery()
 		if list == nil {
 			return false
 		}
@@ -274,32 +246,23 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return false
 	},
 	"tojson": func(value interface{}) template.JS {
-		defer recovery()
 		out, _ := json.Marshal(value)
 		return template.JS(string(out))
 	},
 	"gettitle": func(value string) string {
-		defer recovery()
 		list := strings.Split(value, ".")
 		return list[len(list)-1]
 	},
 	"replace": func(s1 string, s2 string) string {
-		defer recovery()
-
 		return strings.Replace(s2, s1, "", -1)
 	},
 	"findreplace": func(s1 string, s2 string, s3 string) string {
-		defer recovery()
-
 		return strings.Replace(s3, s1, s2, -1)
 	},
 	"title": func(s string) string {
-		defer recovery()
 		return strings.Title(s)
 	},
 	"default": func(arg interface{}, value interface{}) interface{} {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 		switch v.Kind() {
 		case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
@@ -317,8 +280,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return value
 	},
 	"length": func(value interface{}) int {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 		switch v.Kind() {
 		case reflect.Slice, reflect.Array, reflect.Map:
@@ -332,18 +293,12 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return 0
 	},
 	"lower": func(s string) string {
-		defer recovery()
-
 		return strings.ToLower(s)
 	},
 	"upper": func(s string) string {
-		defer recovery()
-
 		return strings.ToUpper(s)
 	},
 	"truncatechars": func(n int, s string) string {
-		defer recovery()
-
 		if n < 0 {
 			return s
 		}
@@ -362,18 +317,12 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return string(r[:n])
 	},
 	"urlencode": func(s string) string {
-		defer recovery()
-
 		return url.QueryEscape(s)
 	},
 	"wordcount": func(s string) int {
-		defer recovery()
-
 		return len(strings.Fields(s))
 	},
 	"divisibleby": func(arg interface{}, value interface{}) bool {
-		defer recovery()
-
 		var v float64
 		switch value.(type) {
 		case int, int8, int16, int32, int64:
@@ -398,11 +347,13 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 			return false
 		}
 
+		if a == 0 {
+			panic("gtf: divisibleby: division by zero")
+		}
+
 		return math.Mod(v, a) == 0
 	},
 	"lengthis": func(arg int, value interface{}) bool {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 		switch v.Kind() {
 		case reflect.Slice, reflect.Array, reflect.Map:
@@ -414,18 +365,12 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return false
 	},
 	"trim": func(s string) string {
-		defer recovery()
-
 		return strings.TrimSpace(s)
 	},
 	"capfirst": func(s string) string {
-		defer recovery()
-
 		return strings.ToUpper(string(s[0])) + s[1:]
 	},
 	"pluralize": func(arg string, value interface{}) string {
-		defer recovery()
-
 		flag := false
 		v := reflect.ValueOf(value)
 		switch v.Kind() {
@@ -454,8 +399,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return bits[1]
 	},
 	"yesno": func(yes string, no string, value bool) string {
-		defer recovery()
-
 		if value {
 			return yes
 		}
@@ -463,8 +406,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return no
 	},
 	"rjust": func(arg int, value string) string {
-		defer recovery()
-
 		n := arg - len([]rune(value))
 
 		if n > 0 {
@@ -474,8 +415,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return value
 	},
 	"ljust": func(arg int, value string) string {
-		defer recovery()
-
 		n := arg - len([]rune(value))
 
 		if n > 0 {
@@ -485,8 +424,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return value
 	},
 	"center": func(arg int, value string) string {
-		defer recovery()
-
 		n := arg - len([]rune(value))
 
 		if n > 0 {
@@ -498,8 +435,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return value
 	},
 	"filesizeformat": func(value interface{}) string {
-		defer recovery()
-
 		var size float64
 
 		v := reflect.ValueOf(value)
@@ -542,8 +477,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return result
 	},
 	"apnumber": func(value interface{}) interface{} {
-		defer recovery()
-
 		name := [10]string{"one", "two", "three", "four", "five",
 			"six", "seven", "eight", "nine"}
 
@@ -562,8 +495,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return value
 	},
 	"intcomma": func(value interface{}) string {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 
 		var x uint
@@ -596,8 +527,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return result
 	},
 	"ordinal": func(value interface{}) string {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 
 		var x uint
@@ -623,8 +552,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return fmt.Sprintf("%d%s", x, suffixes[x%10])
 	},
 	"first": func(value interface{}) interface{} {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 
 		switch v.Kind() {
@@ -637,8 +564,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return ""
 	},
 	"last": func(value interface{}) interface{} {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 
 		switch v.Kind() {
@@ -652,13 +577,9 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return ""
 	},
 	"join": func(arg string, value []string) string {
-		defer recovery()
-
 		return strings.Join(value, arg)
 	},
 	"slice": func(start int, end int, value interface{}) interface{} {
-		defer recovery()
-
 		v := reflect.ValueOf(value)
 
 		if start < 0 {
@@ -680,8 +601,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return ""
 	},
 	"random": func(value interface{}) interface{} {
-		defer recovery()
-
 		rand.Seed(time.Now().UTC().UnixNano())
 
 		v := reflect.ValueOf(value)
@@ -697,8 +616,6 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 		return ""
 	},
 	"randomintrange": func(min, max int, value interface{}) int {
-		defer recovery()
-
 		rand.Seed(time.Now().UTC().UnixNano())
 		return rand.Intn(max-min) + min
 	},
@@ -707,6 +624,12 @@ var GtfTextFuncMap = textTemplate.FuncMap{
 	},
 }
 
+// GtfTextFuncMap wraps gtfRawFuncMap so a panic from invalid input recovers
+// to the zero value, exactly as every gtf function has always behaved. For
+// a variant that surfaces that panic as a real error instead, see
+// GtfStrictTextFuncMap.
+var GtfTextFuncMap = wrapFuncMapLegacy(gtfRawFuncMap)
+
 var GtfFuncMap = htmlTemplate.FuncMap(GtfTextFuncMap)
 
 // gtf.New is a wrapper function of template.New(https://golang.org/pkg/html/template/#New).
@@ -0,0 +1,251 @@
+package gtf
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+
+	textTemplate "text/template"
+)
+
+// inflectionRule is a (pattern, replacement) pair evaluated with
+// regexp.ReplaceAllString, modeled on Rails's ActiveSupport::Inflector
+// rule tables and Hugo's tpl/inflect.
+type inflectionRule struct {
+	pattern     *regexp.Regexp
+	replacement string
+}
+
+// pluralRules and singularRules are tried in order; the first match wins.
+// Rules added via AddPluralRule are prepended so app-registered exceptions
+// take priority over the built-in table.
+var (
+	pluralRules   []inflectionRule
+	singularRules []inflectionRule
+
+	// irregulars maps singular -> plural for nouns the rule table can't
+	// derive mechanically.
+	irregulars       = map[string]string{}
+	irregularPlurals = map[string]string{} // plural -> singular, derived from irregulars
+	uncountables     = map[string]bool{}
+)
+
+func addPluralRuleInternal(pattern, replacement string) {
+	pluralRules = append(pluralRules, inflectionRule{regexp.MustCompile(pattern), replacement})
+}
+
+func addSingularRuleInternal(pattern, replacement string) {
+	singularRules = append(singularRules, inflectionRule{regexp.MustCompile(pattern), replacement})
+}
+
+// AddPluralRule registers an application-specific pluralization rule.
+// match is a regexp (applied case-insensitively via "(?i)") and replace is
+// its replacement, as accepted by regexp.ReplaceAllString (so "${1}" backreferences
+// work). It is checked before the built-in rule table, so it can override
+// default behavior for domain-specific nouns.
+func AddPluralRule(match, replace string) {
+	pluralRules = append([]inflectionRule{{regexp.MustCompile("(?i)" + match), replace}}, pluralRules...)
+}
+
+func init() {
+	for singular, plural := range map[string]string{
+		"child": "children", "person": "people", "man": "men", "woman": "women",
+		"tooth": "teeth", "foot": "feet", "mouse": "mice", "goose": "geese",
+	} {
+		irregulars[singular] = plural
+		irregularPlurals[plural] = singular
+	}
+
+	for _, w := range []string{"sheep", "fish", "series", "species", "money", "rice", "information", "equipment", "moose", "deer"} {
+		uncountables[w] = true
+	}
+
+	addPluralRuleInternal(`(?i)(buffal|tomat)o$`, "${1}oes")
+	addPluralRuleInternal(`(?i)([ti])um$`, "${1}a")
+	addPluralRuleInternal(`(?i)(matr|vert|ind)(?:ix|ex)$`, "${1}ices")
+	addPluralRuleInternal(`(?i)(x|ch|ss|sh)$`, "${1}es")
+	addPluralRuleInternal(`(?i)([^aeiouy])y$`, "${1}ies")
+	addPluralRuleInternal(`(?i)(hive)$`, "${1}s")
+	addPluralRuleInternal(`(?i)([^f])fe$`, "${1}ves")
+	addPluralRuleInternal(`(?i)([lr])f$`, "${1}ves")
+	addPluralRuleInternal(`(?i)sis$`, "ses")
+	addPluralRuleInternal(`(?i)s$`, "s")
+	addPluralRuleInternal(`(?i)$`, "s")
+
+	addSingularRuleInternal(`(?i)([^aeiouy])ies$`, "${1}y")
+	addSingularRuleInternal(`(?i)(x|ch|ss|sh)es$`, "${1}")
+	addSingularRuleInternal(`(?i)([ti])a$`, "${1}um")
+	addSingularRuleInternal(`(?i)(matr)ices$`, "${1}ix")
+	addSingularRuleInternal(`(?i)(vert|ind)ices$`, "${1}ex")
+	addSingularRuleInternal(`(?i)(buffal|tomat)oes$`, "${1}o")
+	addSingularRuleInternal(`(?i)(hive)s$`, "${1}")
+	addSingularRuleInternal(`(?i)([lr])ves$`, "${1}f")
+	addSingularRuleInternal(`(?i)([^f])ves$`, "${1}fe")
+	addSingularRuleInternal(`(?i)ses$`, "sis")
+	addSingularRuleInternal(`(?i)s$`, "")
+}
+
+// capitalize uppercases the first rune of s, leaving the rest untouched.
+// Operating on runes (not bytes) keeps multi-byte first characters intact.
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+func applyRules(word string, rules []inflectionRule) string {
+	for _, r := range rules {
+		if r.pattern.MatchString(word) {
+			return r.pattern.ReplaceAllString(word, r.replacement)
+		}
+	}
+	return word
+}
+
+func pluralizeWord(word string) string {
+	lower := strings.ToLower(word)
+	if uncountables[lower] {
+		return word
+	}
+	if plural, ok := irregulars[lower]; ok {
+		return matchCase(word, plural)
+	}
+	return applyRules(word, pluralRules)
+}
+
+func singularizeWord(word string) string {
+	lower := strings.ToLower(word)
+	if uncountables[lower] {
+		return word
+	}
+	if singular, ok := irregularPlurals[lower]; ok {
+		return matchCase(word, singular)
+	}
+	return applyRules(word, singularRules)
+}
+
+// matchCase capitalizes replacement the way word was capitalized, so
+// "Child" singularizes/pluralizes to "Children" rather than "children".
+func matchCase(word, replacement string) string {
+	if word == strings.ToUpper(word) {
+		return strings.ToUpper(replacement)
+	}
+	if r := []rune(word); len(r) > 0 && unicode.IsUpper(r[0]) {
+		return capitalize(replacement)
+	}
+	return replacement
+}
+
+// splitWords breaks a snake_case, dash-case or CamelCase identifier into
+// its lowercase component words.
+func splitWords(s string) []string {
+	s = strings.ReplaceAll(s, "-", "_")
+
+	var withUnderscores strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			withUnderscores.WriteByte('_')
+		}
+		withUnderscores.WriteRune(r)
+	}
+
+	words := []string{}
+	for _, w := range strings.Split(withUnderscores.String(), "_") {
+		if w != "" {
+			words = append(words, strings.ToLower(w))
+		}
+	}
+	return words
+}
+
+func ordinalizeInt(value interface{}) (string, error) {
+	v := reflect.ValueOf(value)
+
+	var x uint
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return "", fmt.Errorf("gtf: ordinalize: negative value %v", v.Int())
+		}
+		x = uint(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		x = uint(v.Uint())
+	default:
+		return "", fmt.Errorf("gtf: ordinalize: expected an integer, got %T", value)
+	}
+
+	suffixes := [10]string{"th", "st", "nd", "rd", "th", "th", "th", "th", "th", "th"}
+	switch x % 100 {
+	case 11, 12, 13:
+		return fmt.Sprintf("%d%s", x, suffixes[0]), nil
+	}
+	return fmt.Sprintf("%d%s", x, suffixes[x%10]), nil
+}
+
+var GtfInflectFuncMap = textTemplate.FuncMap{
+	// singularize converts a plural English noun to its singular form.
+	"singularize": func(word string) string {
+		return singularizeWord(word)
+	},
+
+	// pluralize1 converts a singular English noun to its plural form. It's
+	// named pluralize1 because "pluralize" is already taken by the
+	// suffix-pair helper in GtfTextFuncMap.
+	"pluralize1": func(word string) string {
+		return pluralizeWord(word)
+	},
+
+	// humanize turns "user_name" into "User name".
+	"humanize": func(word string) string {
+		words := splitWords(word)
+		if len(words) == 0 {
+			return ""
+		}
+		return capitalize(strings.Join(words, " "))
+	},
+
+	// camelize turns "user_name" into "UserName".
+	"camelize": func(word string) string {
+		words := splitWords(word)
+		var b strings.Builder
+		for _, w := range words {
+			b.WriteString(capitalize(w))
+		}
+		return b.String()
+	},
+
+	// underscore turns "UserName" into "user_name".
+	"underscore": func(word string) string {
+		return strings.Join(splitWords(word), "_")
+	},
+
+	// dasherize turns "UserName" into "user-name".
+	"dasherize": func(word string) string {
+		return strings.Join(splitWords(word), "-")
+	},
+
+	// titleize turns "user_name" into "User Name".
+	"titleize": func(word string) string {
+		words := splitWords(word)
+		for i, w := range words {
+			words[i] = capitalize(w)
+		}
+		return strings.Join(words, " ")
+	},
+
+	// ordinalize turns 1 into "1st", 2 into "2nd", and so on.
+	"ordinalize": func(value interface{}) (string, error) {
+		return ordinalizeInt(value)
+	},
+}
+
+func init() {
+	for k, v := range GtfInflectFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
@@ -0,0 +1,53 @@
+package gtf
+
+import "testing"
+
+func TestSingularizePluralizeRoundTrip(t *testing.T) {
+	cases := []struct {
+		singular string
+		plural   string
+	}{
+		{"matrix", "matrices"},
+		{"vertex", "vertices"},
+		{"index", "indices"},
+		{"child", "children"},
+		{"box", "boxes"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.singular, func(t *testing.T) {
+			if got := pluralizeWord(c.singular); got != c.plural {
+				t.Errorf("pluralizeWord(%q) = %q, want %q", c.singular, got, c.plural)
+			}
+			if got := singularizeWord(c.plural); got != c.singular {
+				t.Errorf("singularizeWord(%q) = %q, want %q", c.plural, got, c.singular)
+			}
+		})
+	}
+}
+
+func TestCapitalizeUTF8(t *testing.T) {
+	cases := []struct {
+		in   string
+		want string
+	}{
+		{"émile", "Émile"},
+		{"straße", "Straße"},
+		{"", ""},
+		{"ascii", "Ascii"},
+	}
+
+	for _, c := range cases {
+		if got := capitalize(c.in); got != c.want {
+			t.Errorf("capitalize(%q) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
+
+func TestHumanizeUTF8FirstChar(t *testing.T) {
+	humanizeFn := GtfInflectFuncMap["humanize"].(func(string) string)
+
+	if got := humanizeFn("émile_zola"); got != "Émile zola" {
+		t.Errorf("humanize(émile_zola) = %q, want %q", got, "Émile zola")
+	}
+}
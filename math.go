@@ -0,0 +1,200 @@
+package gtf
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	textTemplate "text/template"
+)
+
+// numKind tracks whether an operand was floating point, so arithmetic
+// helpers can decide whether to report their result as int64 or float64.
+type numKind struct {
+	f       float64
+	isFloat bool
+}
+
+func toNum(value interface{}) (numKind, error) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return numKind{f: float64(v.Int())}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return numKind{f: float64(v.Uint())}, nil
+	case reflect.Float32, reflect.Float64:
+		return numKind{f: v.Float(), isFloat: true}, nil
+	}
+	return numKind{}, fmt.Errorf("gtf: expected a number, got %T", value)
+}
+
+// numResult returns n.f as int64 unless any input was float, in which case
+// it stays float64 -- matching Hugo's tpl/math promotion rule.
+func numResult(n numKind) interface{} {
+	if n.isFloat {
+		return n.f
+	}
+	return int64(n.f)
+}
+
+func reduceNums(fn func(a, b float64) float64, values ...interface{}) (interface{}, error) {
+	if len(values) == 0 {
+		return nil, fmt.Errorf("gtf: expected at least one numeric argument")
+	}
+
+	acc, err := toNum(values[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range values[1:] {
+		n, err := toNum(value)
+		if err != nil {
+			return nil, err
+		}
+		acc = numKind{f: fn(acc.f, n.f), isFloat: acc.isFloat || n.isFloat}
+	}
+
+	return numResult(acc), nil
+}
+
+var GtfMathFuncMap = textTemplate.FuncMap{
+	"add": func(values ...interface{}) (interface{}, error) {
+		return reduceNums(func(a, b float64) float64 { return a + b }, values...)
+	},
+	"sub": func(values ...interface{}) (interface{}, error) {
+		return reduceNums(func(a, b float64) float64 { return a - b }, values...)
+	},
+	"mul": func(values ...interface{}) (interface{}, error) {
+		return reduceNums(func(a, b float64) float64 { return a * b }, values...)
+	},
+	"div": func(a, b interface{}) (interface{}, error) {
+		an, err := toNum(a)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: div: %w", err)
+		}
+		bn, err := toNum(b)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: div: %w", err)
+		}
+		if bn.f == 0 {
+			return nil, fmt.Errorf("gtf: div: division by zero")
+		}
+		return numResult(numKind{f: an.f / bn.f, isFloat: an.isFloat || bn.isFloat}), nil
+	},
+	"mod": func(a, b interface{}) (interface{}, error) {
+		an, err := toNum(a)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: mod: %w", err)
+		}
+		bn, err := toNum(b)
+		if err != nil {
+			return nil, fmt.Errorf("gtf: mod: %w", err)
+		}
+		if bn.f == 0 {
+			return nil, fmt.Errorf("gtf: mod: division by zero")
+		}
+		return numResult(numKind{f: math.Mod(an.f, bn.f), isFloat: an.isFloat || bn.isFloat}), nil
+	},
+	"round": func(value interface{}, precision ...int) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: round: %w", err)
+		}
+		p := 0
+		if len(precision) > 0 {
+			p = precision[0]
+		}
+		mult := math.Pow(10, float64(p))
+		return math.Round(n.f*mult) / mult, nil
+	},
+	"ceil": func(value interface{}, precision ...int) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: ceil: %w", err)
+		}
+		p := 0
+		if len(precision) > 0 {
+			p = precision[0]
+		}
+		mult := math.Pow(10, float64(p))
+		return math.Ceil(n.f*mult) / mult, nil
+	},
+	"floor": func(value interface{}, precision ...int) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: floor: %w", err)
+		}
+		p := 0
+		if len(precision) > 0 {
+			p = precision[0]
+		}
+		mult := math.Pow(10, float64(p))
+		return math.Floor(n.f*mult) / mult, nil
+	},
+	"min": func(values ...interface{}) (interface{}, error) {
+		return reduceNums(math.Min, values...)
+	},
+	"max": func(values ...interface{}) (interface{}, error) {
+		return reduceNums(math.Max, values...)
+	},
+	"abs": func(value interface{}) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: abs: %w", err)
+		}
+		return math.Abs(n.f), nil
+	},
+	"pow": func(base, exp interface{}) (float64, error) {
+		b, err := toNum(base)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: pow: %w", err)
+		}
+		e, err := toNum(exp)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: pow: %w", err)
+		}
+		return math.Pow(b.f, e.f), nil
+	},
+	"sqrt": func(value interface{}) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: sqrt: %w", err)
+		}
+		if n.f < 0 {
+			return 0, fmt.Errorf("gtf: sqrt: negative argument %v", n.f)
+		}
+		return math.Sqrt(n.f), nil
+	},
+	"log": func(value interface{}) (float64, error) {
+		n, err := toNum(value)
+		if err != nil {
+			return 0, fmt.Errorf("gtf: log: %w", err)
+		}
+		if n.f <= 0 {
+			return 0, fmt.Errorf("gtf: log: non-positive argument %v", n.f)
+		}
+		return math.Log(n.f), nil
+	},
+	"inRange": func(min, max, value interface{}) (bool, error) {
+		minN, err := toNum(min)
+		if err != nil {
+			return false, fmt.Errorf("gtf: inRange: %w", err)
+		}
+		maxN, err := toNum(max)
+		if err != nil {
+			return false, fmt.Errorf("gtf: inRange: %w", err)
+		}
+		valN, err := toNum(value)
+		if err != nil {
+			return false, fmt.Errorf("gtf: inRange: %w", err)
+		}
+		return valN.f >= minN.f && valN.f <= maxN.f, nil
+	},
+}
+
+func init() {
+	for k, v := range GtfMathFuncMap {
+		GtfTextFuncMap[k] = v
+	}
+}
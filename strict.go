@@ -0,0 +1,180 @@
+package gtf
+
+import (
+	"fmt"
+	htmlTemplate "html/template"
+	"reflect"
+	textTemplate "text/template"
+)
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+// wrapFuncMapLegacy wraps every function in raw so a panic recovers to the
+// zero value, preserving each function's original signature. This is how
+// gtf has always behaved, just generated instead of hand-written per
+// function via a "defer recovery()" at the top of each closure.
+func wrapFuncMapLegacy(raw textTemplate.FuncMap) textTemplate.FuncMap {
+	wrapped := make(textTemplate.FuncMap, len(raw))
+	for name, fn := range raw {
+		wrapped[name] = wrapLegacy(fn)
+	}
+	return wrapped
+}
+
+func wrapLegacy(fn interface{}) interface{} {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+
+	return reflect.MakeFunc(ft, func(args []reflect.Value) (results []reflect.Value) {
+		defer func() {
+			if recover() != nil {
+				results = zeroResults(ft)
+			}
+		}()
+		if ft.IsVariadic() {
+			// As in wrapStrict: MakeFunc hands the variadic arguments over
+			// as one slice Value, which CallSlice forwards unchanged.
+			return fv.CallSlice(args)
+		}
+		return fv.Call(args)
+	}).Interface()
+}
+
+func zeroResults(ft reflect.Type) []reflect.Value {
+	results := make([]reflect.Value, ft.NumOut())
+	for i := range results {
+		results[i] = reflect.Zero(ft.Out(i))
+	}
+	return results
+}
+
+// wrapStrict rewraps fn so a panic (or, if fn doesn't already return an
+// error, its absence of one) surfaces as a real error instead of being
+// discarded: a function with no error return gains one, and a function
+// that already returns one keeps reporting it, but a panic that would
+// otherwise have escaped is also turned into that same error.
+func wrapStrict(name string, fn interface{}) interface{} {
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+
+	numOut := ft.NumOut()
+	hasErr := numOut > 0 && ft.Out(numOut-1) == errorType
+
+	outTypes := make([]reflect.Type, numOut)
+	for i := 0; i < numOut; i++ {
+		outTypes[i] = ft.Out(i)
+	}
+	if !hasErr {
+		outTypes = append(outTypes, errorType)
+	}
+
+	wrapperType := reflect.FuncOf(inTypes(ft), outTypes, ft.IsVariadic())
+
+	zero := func() []reflect.Value {
+		results := make([]reflect.Value, len(outTypes))
+		for i := range results {
+			results[i] = reflect.Zero(outTypes[i])
+		}
+		return results
+	}
+
+	return reflect.MakeFunc(wrapperType, func(args []reflect.Value) (results []reflect.Value) {
+		defer func() {
+			if r := recover(); r != nil {
+				results = zero()
+				results[len(results)-1] = reflect.ValueOf(fmt.Errorf("gtf: %s: %v", name, r))
+			}
+		}()
+
+		var out []reflect.Value
+		if ft.IsVariadic() {
+			// reflect.MakeFunc collects a variadic function's trailing
+			// arguments into a single slice Value, so forwarding them to fv
+			// (variadic with the same trailing parameter type) needs
+			// CallSlice, not Call, to avoid re-spreading that slice as one
+			// argument too many.
+			out = fv.CallSlice(args)
+		} else {
+			out = fv.Call(args)
+		}
+		results = make([]reflect.Value, len(outTypes))
+		copy(results, out)
+		if !hasErr {
+			results[numOut] = reflect.Zero(errorType)
+		}
+		return results
+	}).Interface()
+}
+
+func inTypes(ft reflect.Type) []reflect.Type {
+	in := make([]reflect.Type, ft.NumIn())
+	for i := range in {
+		in[i] = ft.In(i)
+	}
+	return in
+}
+
+// GtfStrictTextFuncMap is GtfTextFuncMap's strict counterpart: every
+// function is rewrapped so invalid input (an empty slice to first, a
+// non-*bool to istrue, a zero divisor to divisibleby, ...) surfaces as a
+// real error instead of silently recovering to the zero value, the same
+// way text/template already propagates an error returned by any func.
+// Build a template with it via NewStrict/InjectStrict.
+var GtfStrictTextFuncMap = buildStrictFuncMap()
+
+// GtfStrictFuncMap is GtfStrictTextFuncMap converted for html/template.
+var GtfStrictFuncMap = htmlTemplate.FuncMap(GtfStrictTextFuncMap)
+
+func buildStrictFuncMap() textTemplate.FuncMap {
+	raw := textTemplate.FuncMap{}
+	for _, group := range []textTemplate.FuncMap{
+		gtfRawFuncMap,
+		GtfCollectionFuncMap,
+		GtfCryptoFuncMap,
+		GtfMathFuncMap,
+		GtfDictFuncMap,
+		GtfDurationFuncMap,
+		GtfInflectFuncMap,
+	} {
+		for k, v := range group {
+			raw[k] = v
+		}
+	}
+
+	strict := make(textTemplate.FuncMap, len(raw))
+	for name, fn := range raw {
+		strict[name] = wrapStrict(name, fn)
+	}
+	return strict
+}
+
+func init() {
+	// apply looks up its target function by name at call time, so the
+	// generic wrapping above only makes apply itself strict, not the
+	// function it dispatches to. Rebuild it against GtfStrictTextFuncMap so
+	// e.g. apply list "capfirst" surfaces errors the same way calling
+	// capfirst directly through the strict map would. This has to happen in
+	// init(), after GtfStrictTextFuncMap's own initializer has run, to
+	// avoid a package-level initialization cycle.
+	GtfStrictTextFuncMap["apply"] = wrapStrict("apply", applyWith(func(name string) (interface{}, bool) {
+		fn, ok := GtfStrictTextFuncMap[name]
+		return fn, ok
+	}))
+}
+
+// NewStrict is like New, but templates built with it surface invalid input
+// to a gtf function as a real error from Execute instead of silently
+// treating it as the zero value.
+func NewStrict(name string) *htmlTemplate.Template {
+	return htmlTemplate.New(name).Funcs(GtfStrictFuncMap)
+}
+
+// InjectStrict is like Inject, but injects the strict variant of gtf
+// functions (see GtfStrictFuncMap).
+func InjectStrict(funcs map[string]interface{}) {
+	for k, v := range GtfStrictFuncMap {
+		if _, ok := funcs[k]; !ok {
+			funcs[k] = v
+		}
+	}
+}
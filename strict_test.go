@@ -0,0 +1,38 @@
+package gtf
+
+import "testing"
+
+func TestStrictVariadicFunc(t *testing.T) {
+	dictFn := GtfStrictTextFuncMap["dict"].(func(...interface{}) (map[string]interface{}, error))
+
+	got, err := dictFn("a", 1, "b", 2)
+	if err != nil {
+		t.Fatalf("dict: unexpected error: %v", err)
+	}
+	if got["a"] != 1 || got["b"] != 2 {
+		t.Errorf("dict(a, 1, b, 2) = %v, want map[a:1 b:2]", got)
+	}
+
+	if _, err := dictFn("a"); err == nil {
+		t.Errorf("dict: expected error for odd argument count")
+	}
+}
+
+func TestStrictApplyDispatchesThroughStrictFuncMap(t *testing.T) {
+	applyFn := GtfStrictTextFuncMap["apply"].(func(interface{}, string, ...interface{}) ([]interface{}, error))
+
+	if _, err := applyFn([]interface{}{""}, "capfirst"); err == nil {
+		t.Errorf("apply: expected capfirst's panic on empty string to surface as an error in strict mode")
+	}
+
+	got, err := applyFn([]interface{}{"a", "b"}, "upper")
+	if err != nil {
+		t.Fatalf("apply: unexpected error: %v", err)
+	}
+	want := []interface{}{"A", "B"}
+	for i, w := range want {
+		if got[i] != w {
+			t.Errorf("apply(upper) = %v, want %v", got, want)
+		}
+	}
+}